@@ -0,0 +1,137 @@
+package cloudinary
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UploadParams are the same fields set by the UploadImage Opt functions,
+// reused here so PresignUpload and UploadImage share one configuration
+// surface.
+type UploadParams = UploadOptions
+
+// PresignUpload returns a URL and the form fields a browser or mobile
+// client can POST directly to Cloudinary, without the server ever
+// handing that client apiSecret.
+//
+// expiresIn does not become part of the signed or posted fields:
+// Cloudinary itself rejects a signed upload once its timestamp is older
+// than the staleness window configured on the account (commonly one
+// hour), so a client-supplied expiry has nothing to bind to server-side.
+// It is accepted here only so callers can apply the same deadline
+// locally, e.g. to stop offering a stale upload form.
+//
+// The returned url.Values keys are the exact, lowercase field names
+// Cloudinary expects (api_key, public_id, timestamp, ...); callers must
+// send them verbatim rather than through something that canonicalizes
+// keys, like http.Header.
+func (c *Client) PresignUpload(params UploadParams, expiresIn time.Duration) (string, url.Values, error) {
+	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+
+	var optMap map[string]interface{}
+	optByte, err := json.Marshal(&params)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := json.Unmarshal(optByte, &optMap); err != nil {
+		return "", nil, err
+	}
+
+	signParams := map[string]string{"timestamp": timestamp}
+	for field, val := range optMap {
+		signParams[field] = fmt.Sprintf("%v", val)
+	}
+
+	fields := url.Values{}
+	for field, val := range signParams {
+		fields.Set(field, val)
+	}
+	fields.Set("api_key", c.apiKey)
+	fields.Set("signature", sign(signParams, c.apiSecret, params.sigAlgo))
+
+	uploadURL := fmt.Sprintf("%simage/upload", c.BaseURL.String())
+	return uploadURL, fields, nil
+}
+
+// deliveryOptions configures SignedURL.
+type deliveryOptions struct {
+	transformation string
+	version        int64
+	format         string
+	expiresAt      *time.Time
+}
+
+// DeliveryOpt configures SignedURL.
+type DeliveryOpt func(do *deliveryOptions)
+
+// WithTransformation inserts a Cloudinary transformation string (e.g.
+// "w_300,h_200,c_fill") into the delivery URL.
+func WithTransformation(transformation string) DeliveryOpt {
+	return func(do *deliveryOptions) {
+		do.transformation = transformation
+	}
+}
+
+// WithVersion pins the delivery URL to a specific asset version.
+func WithVersion(version int64) DeliveryOpt {
+	return func(do *deliveryOptions) {
+		do.version = version
+	}
+}
+
+// WithFormat appends a file extension to the delivered asset.
+func WithFormat(format string) DeliveryOpt {
+	return func(do *deliveryOptions) {
+		do.format = format
+	}
+}
+
+// WithExpiration makes the URL time-limited, signed as t_{unix} and
+// rejected by Cloudinary once t has passed.
+func WithExpiration(t time.Time) DeliveryOpt {
+	return func(do *deliveryOptions) {
+		do.expiresAt = &t
+	}
+}
+
+// SignedURL builds a signed delivery URL of the form
+// https://res.cloudinary.com/{cloud}/image/upload/s--{sig}--/{transformations}/{public_id}.{fmt},
+// where sig is the first 8 characters of
+// base64url(sha1(transformations + "/" + public_id + apiSecret)).
+func (c *Client) SignedURL(publicID string, opts ...DeliveryOpt) string {
+	do := &deliveryOptions{}
+	for _, o := range opts {
+		o(do)
+	}
+
+	path := strings.Trim(do.transformation, "/")
+	if do.expiresAt != nil {
+		path = strings.Trim(fmt.Sprintf("t_%d/%s", do.expiresAt.Unix(), path), "/")
+	}
+
+	toSign := path + "/" + publicID + c.apiSecret
+	sum := sha1.Sum([]byte(toSign))
+	sig := base64.RawURLEncoding.EncodeToString(sum[:])[:8]
+
+	segments := []string{"image", "upload", "s--" + sig + "--"}
+	if path != "" {
+		segments = append(segments, path)
+	}
+	if do.version > 0 {
+		segments = append(segments, fmt.Sprintf("v%d", do.version))
+	}
+
+	filename := publicID
+	if do.format != "" {
+		filename += "." + do.format
+	}
+	segments = append(segments, filename)
+
+	return fmt.Sprintf("https://res.cloudinary.com/%s/%s", c.cloudName, strings.Join(segments, "/"))
+}
@@ -0,0 +1,174 @@
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIErrorCode classifies an ErrorResponse by HTTP status and message
+// pattern, similar to how S3-compatible servers expose an APIErrorCode
+// enum, so callers can branch on the failure without string-matching
+// ErrorData.Message themselves.
+type APIErrorCode int
+
+const (
+	ErrUnknown APIErrorCode = iota
+	ErrRateLimited
+	ErrInvalidSignature
+	ErrResourceNotFound
+	ErrInvalidImageFile
+	ErrUploadTooLarge
+)
+
+// Code classifies r by HTTP status, falling back to matching known
+// phrases in ErrorData.Message for the statuses Cloudinary overloads
+// (400 covers both invalid images and malformed params).
+func (r *ErrorResponse) Code() APIErrorCode {
+	if r.Response == nil {
+		return ErrUnknown
+	}
+
+	switch r.Response.StatusCode {
+	case http.StatusTooManyRequests, 420:
+		return ErrRateLimited
+	case http.StatusUnauthorized:
+		return ErrInvalidSignature
+	case http.StatusNotFound:
+		return ErrResourceNotFound
+	case http.StatusBadRequest:
+		msg := strings.ToLower(r.ErrorData.Message)
+		switch {
+		case strings.Contains(msg, "invalid image file"):
+			return ErrInvalidImageFile
+		case strings.Contains(msg, "file size too large"), strings.Contains(msg, "exceeds the maximum"):
+			return ErrUploadTooLarge
+		}
+	}
+
+	return ErrUnknown
+}
+
+// IsRetryable reports whether err represents a failure that is likely to
+// succeed if retried: Cloudinary rate limiting, 5xx responses, or a
+// network-level timeout. Other network errors are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var er *ErrorResponse
+	if errors.As(err, &er) {
+		if er.Code() == ErrRateLimited {
+			return true
+		}
+		return er.Response != nil && er.Response.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	return false
+}
+
+// RetryAfter reads the delay Cloudinary asked for via the Retry-After or
+// X-RateLimit-Reset response headers, returning 0 when err carries
+// neither.
+func RetryAfter(err error) time.Duration {
+	var er *ErrorResponse
+	if !errors.As(err, &er) || er.Response == nil {
+		return 0
+	}
+
+	if v := er.Response.Header.Get("Retry-After"); v != "" {
+		if secs, perr := strconv.Atoi(v); perr == nil {
+			return time.Duration(secs) * time.Second
+		}
+	}
+
+	if v := er.Response.Header.Get("X-RateLimit-Reset"); v != "" {
+		if unix, perr := strconv.ParseInt(v, 10, 64); perr == nil {
+			if d := time.Until(time.Unix(unix, 0)); d > 0 {
+				return d
+			}
+		}
+	}
+
+	return 0
+}
+
+// RetryPolicy configures Client.doWithRetry.
+type RetryPolicy struct {
+	// MaxRetries is the number of additional attempts made after the
+	// first failed one.
+	MaxRetries int
+	// BaseDelay is the backoff used for the first retry; it doubles on
+	// each subsequent attempt up to MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff, before jitter is applied.
+	MaxDelay time.Duration
+}
+
+// defaultRetryPolicy is used by Client when WithRetryPolicy is not
+// given.
+var defaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   10 * time.Second,
+}
+
+// doWithRetry calls newReq to build a fresh request for every attempt
+// (so a request whose body was already consumed is rebuilt rather than
+// resent) and retries on IsRetryable errors using c.retryPolicy,
+// honoring any server-requested RetryAfter delay.
+func (c *Client) doWithRetry(ctx context.Context, newReq func() (*http.Request, error), v interface{}) (*Response, error) {
+	policy := c.retryPolicy
+	if policy == (RetryPolicy{}) {
+		policy = defaultRetryPolicy
+	}
+
+	var (
+		resp *Response
+		err  error
+	)
+
+	for attempt := 0; ; attempt++ {
+		req, rerr := newReq()
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		resp, err = c.Do(ctx, req, v)
+		if err == nil || attempt >= policy.MaxRetries || !IsRetryable(err) {
+			return resp, err
+		}
+
+		delay := RetryAfter(err)
+		if delay == 0 {
+			delay = backoffDelay(policy, attempt)
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		}
+	}
+}
+
+// backoffDelay computes a jittered exponential backoff for attempt,
+// capped at policy.MaxDelay.
+func backoffDelay(policy RetryPolicy, attempt int) time.Duration {
+	d := policy.BaseDelay * time.Duration(uint(1)<<uint(attempt))
+	if d > policy.MaxDelay {
+		d = policy.MaxDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
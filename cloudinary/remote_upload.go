@@ -0,0 +1,143 @@
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrRemoteFetchUnauthorized is returned when the injected AWS/GCS client
+// reports an authorization failure while streaming an s3:// or gs://
+// source (WithStreamRemote). Failures reported by Cloudinary's own
+// remote-fetch upload are returned as *ErrorResponse instead.
+var ErrRemoteFetchUnauthorized = errors.New("cloudinary: remote fetch unauthorized")
+
+// ErrRemoteObjectNotFound is returned when the injected AWS/GCS client
+// reports that the referenced s3:// or gs:// object does not exist while
+// streaming it (WithStreamRemote). Failures reported by Cloudinary's own
+// remote-fetch upload are returned as *ErrorResponse instead.
+var ErrRemoteObjectNotFound = errors.New("cloudinary: remote object not found")
+
+// uploadFromS3 uploads an s3://bucket/key source. By default the URL is
+// signed and passed straight through to Cloudinary's remote-fetch
+// upload, which natively accepts s3:// URLs when the account is
+// configured for it. If WithStreamRemote(true) is set, the object is
+// instead read with the injected AWS client and streamed into a chunked
+// upload without touching local disk.
+func (us *UploadService) uploadFromS3(ctx context.Context, url string, request *UploadRequest, opt *UploadOptions) (*UploadResponse, *Response, error) {
+	if !us.client.streamRemote {
+		return us.uploadFromURL(ctx, url, request, opt)
+	}
+
+	if us.client.awsConfig == nil {
+		return nil, nil, errors.New("cloudinary: WithStreamRemote requires WithAWSConfig for s3:// sources")
+	}
+
+	bucket, key, err := parseRemoteURI(request.File, "s3://")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s3Client := s3.NewFromConfig(*us.client.awsConfig)
+	out, err := s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, nil, mapS3Error(err)
+	}
+	defer out.Body.Close()
+
+	if out.ContentLength == nil {
+		return nil, nil, fmt.Errorf("cloudinary: s3://%s/%s did not report a Content-Length, required to stream it as a chunked upload", bucket, key)
+	}
+
+	co := &ChunkedUploadOptions{ChunkSize: defaultChunkSize}
+	return us.uploadChunks(ctx, url, request, opt, out.Body, *out.ContentLength, co)
+}
+
+// uploadFromGoogleStorage uploads a gs://bucket/object source, mirroring
+// uploadFromS3's remote-fetch-by-default / stream-when-asked behavior.
+func (us *UploadService) uploadFromGoogleStorage(ctx context.Context, url string, request *UploadRequest, opt *UploadOptions) (*UploadResponse, *Response, error) {
+	if !us.client.streamRemote {
+		return us.uploadFromURL(ctx, url, request, opt)
+	}
+
+	if us.client.gcsClient == nil {
+		return nil, nil, errors.New("cloudinary: WithStreamRemote requires WithGCSClient for gs:// sources")
+	}
+
+	bucket, object, err := parseRemoteURI(request.File, "gs://")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader, err := us.client.gcsClient.Bucket(bucket).Object(object).NewReader(ctx)
+	if err != nil {
+		return nil, nil, mapGCSError(err)
+	}
+	defer reader.Close()
+
+	co := &ChunkedUploadOptions{ChunkSize: defaultChunkSize}
+	return us.uploadChunks(ctx, url, request, opt, reader, reader.Attrs.Size, co)
+}
+
+// parseRemoteURI splits a "scheme://bucket/key" URI into its bucket and
+// key components.
+func parseRemoteURI(uri, scheme string) (bucket, key string, err error) {
+	trimmed := strings.TrimPrefix(uri, scheme)
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("cloudinary: %q is not a valid %sbucket/key URI", uri, scheme)
+	}
+	return parts[0], parts[1], nil
+}
+
+// mapS3Error translates the aws-sdk-go-v2 errors worth distinguishing
+// into the typed errors above.
+func mapS3Error(err error) error {
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return ErrRemoteObjectNotFound
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		switch respErr.HTTPStatusCode() {
+		case 404:
+			return ErrRemoteObjectNotFound
+		case 401, 403:
+			return ErrRemoteFetchUnauthorized
+		}
+	}
+
+	return err
+}
+
+// mapGCSError translates cloud.google.com/go/storage errors into the
+// typed errors above.
+func mapGCSError(err error) error {
+	if errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, storage.ErrBucketNotExist) {
+		return ErrRemoteObjectNotFound
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case 404:
+			return ErrRemoteObjectNotFound
+		case 401, 403:
+			return ErrRemoteFetchUnauthorized
+		}
+	}
+
+	return err
+}
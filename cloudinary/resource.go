@@ -0,0 +1,410 @@
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResourceService handles communication with the resource management
+// endpoints of the Cloudinary Admin API, mounted at Client.Resources.
+type ResourceService service
+
+// ErrPublicIDNotFound is returned by Destroy and Rename when the given
+// public ID does not exist.
+var ErrPublicIDNotFound = errors.New("cloudinary: public id not found")
+
+// notFoundErr translates a "resource not found" API response into
+// ErrPublicIDNotFound, leaving every other error untouched.
+func notFoundErr(err error) error {
+	var er *ErrorResponse
+	if errors.As(err, &er) && er.Code() == ErrResourceNotFound {
+		return ErrPublicIDNotFound
+	}
+	return err
+}
+
+type destroyOptions struct {
+	resourceType string
+	deliveryType string
+	invalidate   *bool
+}
+
+// DestroyOpt configures Destroy.
+type DestroyOpt func(do *destroyOptions)
+
+func WithDestroyResourceType(resourceType string) DestroyOpt {
+	return func(do *destroyOptions) {
+		do.resourceType = resourceType
+	}
+}
+
+func WithDestroyType(deliveryType string) DestroyOpt {
+	return func(do *destroyOptions) {
+		do.deliveryType = deliveryType
+	}
+}
+
+func WithDestroyInvalidate(invalidate bool) DestroyOpt {
+	return func(do *destroyOptions) {
+		do.invalidate = &invalidate
+	}
+}
+
+// DestroyRequest is the signed body sent to POST /{resource_type}/destroy.
+type DestroyRequest struct {
+	PublicId   string `json:"public_id"`
+	Type       string `json:"type,omitempty"`
+	Invalidate *bool  `json:"invalidate,omitempty"`
+	Timestamp  string `json:"timestamp"`
+	APIKey     string `json:"api_key"`
+	Signature  string `json:"signature"`
+}
+
+// DestroyResponse reports the outcome of a Destroy call.
+type DestroyResponse struct {
+	Result string `json:"result"`
+}
+
+// Destroy deletes the resource identified by publicID.
+func (rs *ResourceService) Destroy(ctx context.Context, publicID string, opts ...DestroyOpt) (*DestroyResponse, *Response, error) {
+	do := &destroyOptions{resourceType: "image", deliveryType: "upload"}
+	for _, o := range opts {
+		o(do)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+	signParams := map[string]string{
+		"public_id": publicID,
+		"timestamp": timestamp,
+	}
+	if do.deliveryType != "" {
+		signParams["type"] = do.deliveryType
+	}
+	if do.invalidate != nil {
+		signParams["invalidate"] = strconv.FormatBool(*do.invalidate)
+	}
+
+	body := &DestroyRequest{
+		PublicId:   publicID,
+		Type:       do.deliveryType,
+		Invalidate: do.invalidate,
+		Timestamp:  timestamp,
+		APIKey:     rs.client.apiKey,
+		Signature:  sign(signParams, rs.client.apiSecret, SignatureSHA1),
+	}
+
+	req, err := rs.client.NewRequest("POST", fmt.Sprintf("%s/destroy", do.resourceType), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dr := new(DestroyResponse)
+	resp, err := rs.client.Do(ctx, req, dr)
+	if err != nil {
+		return nil, resp, notFoundErr(err)
+	}
+	if dr.Result == "not found" {
+		return dr, resp, ErrPublicIDNotFound
+	}
+	return dr, resp, nil
+}
+
+// RenameRequest is the signed body sent to POST /{resource_type}/rename.
+type RenameRequest struct {
+	FromPublicId string `json:"from_public_id"`
+	ToPublicId   string `json:"to_public_id"`
+	Overwrite    *bool  `json:"overwrite,omitempty"`
+	Timestamp    string `json:"timestamp"`
+	APIKey       string `json:"api_key"`
+	Signature    string `json:"signature"`
+}
+
+type renameOptions struct {
+	resourceType string
+	overwrite    *bool
+}
+
+// RenameOpt configures Rename.
+type RenameOpt func(ro *renameOptions)
+
+func WithRenameResourceType(resourceType string) RenameOpt {
+	return func(ro *renameOptions) {
+		ro.resourceType = resourceType
+	}
+}
+
+func WithRenameOverwrite(overwrite bool) RenameOpt {
+	return func(ro *renameOptions) {
+		ro.overwrite = &overwrite
+	}
+}
+
+// Rename moves a resource from one public ID to another.
+func (rs *ResourceService) Rename(ctx context.Context, fromPublicID, toPublicID string, opts ...RenameOpt) (*UploadResponse, *Response, error) {
+	ro := &renameOptions{resourceType: "image"}
+	for _, o := range opts {
+		o(ro)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+	signParams := map[string]string{
+		"from_public_id": fromPublicID,
+		"to_public_id":   toPublicID,
+		"timestamp":      timestamp,
+	}
+	if ro.overwrite != nil {
+		signParams["overwrite"] = strconv.FormatBool(*ro.overwrite)
+	}
+
+	body := &RenameRequest{
+		FromPublicId: fromPublicID,
+		ToPublicId:   toPublicID,
+		Overwrite:    ro.overwrite,
+		Timestamp:    timestamp,
+		APIKey:       rs.client.apiKey,
+		Signature:    sign(signParams, rs.client.apiSecret, SignatureSHA1),
+	}
+
+	req, err := rs.client.NewRequest("POST", fmt.Sprintf("%s/rename", ro.resourceType), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ur := new(UploadResponse)
+	resp, err := rs.client.Do(ctx, req, ur)
+	if err != nil {
+		return nil, resp, notFoundErr(err)
+	}
+	return ur, resp, nil
+}
+
+// ListOptions filters and paginates List.
+type ListOptions struct {
+	Prefix     *string `url:"prefix,omitempty"`
+	MaxResults *int    `url:"max_results,omitempty"`
+	NextCursor *string `url:"next_cursor,omitempty"`
+	Tags       *bool   `url:"tags,omitempty"`
+}
+
+// Resource describes a single asset returned by List.
+type Resource struct {
+	PublicId     string `json:"public_id"`
+	Format       string `json:"format"`
+	Version      int64  `json:"version"`
+	ResourceType string `json:"resource_type"`
+	Type         string `json:"type"`
+	CreatedAt    string `json:"created_at"`
+	Bytes        int64  `json:"bytes"`
+	Width        int64  `json:"width"`
+	Height       int64  `json:"height"`
+	URL          string `json:"url"`
+	SecureURL    string `json:"secure_url"`
+}
+
+// ListResponse is a single page of List results.
+type ListResponse struct {
+	Resources  []Resource `json:"resources"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// List fetches a single page of resources of the given resourceType
+// (e.g. "image") and deliveryType (e.g. "upload"). Use NewListIterator
+// to transparently follow next_cursor across pages.
+func (rs *ResourceService) List(ctx context.Context, resourceType, deliveryType string, opt *ListOptions) (*ListResponse, *Response, error) {
+	u, err := addOptions(fmt.Sprintf("resources/%s/%s", resourceType, deliveryType), opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lr := new(ListResponse)
+	resp, err := rs.client.doWithRetry(ctx, func() (*http.Request, error) {
+		return rs.client.NewAdminRequest("GET", u, nil)
+	}, lr)
+	if err != nil {
+		return nil, resp, err
+	}
+	return lr, resp, nil
+}
+
+// ListIterator transparently follows the next_cursor tokens returned by
+// List, similar to how object-store SDKs iterate paged bucket listings.
+type ListIterator struct {
+	rs           *ResourceService
+	resourceType string
+	deliveryType string
+	opt          ListOptions
+	done         bool
+}
+
+// NewListIterator returns a ListIterator starting from the first page.
+func (rs *ResourceService) NewListIterator(resourceType, deliveryType string, opt ListOptions) *ListIterator {
+	return &ListIterator{rs: rs, resourceType: resourceType, deliveryType: deliveryType, opt: opt}
+}
+
+// Next fetches the next page of resources, returning an empty slice once
+// the listing is exhausted.
+func (it *ListIterator) Next(ctx context.Context) ([]Resource, error) {
+	if it.done {
+		return nil, nil
+	}
+
+	lr, _, err := it.rs.List(ctx, it.resourceType, it.deliveryType, &it.opt)
+	if err != nil {
+		return nil, err
+	}
+
+	if lr.NextCursor == "" {
+		it.done = true
+	} else {
+		cursor := lr.NextCursor
+		it.opt.NextCursor = &cursor
+	}
+
+	return lr.Resources, nil
+}
+
+// TagRequest is the signed body sent to POST /{resource_type}/tags.
+type TagRequest struct {
+	Command   string `json:"command"`
+	Tag       string `json:"tag"`
+	PublicIds string `json:"public_ids"`
+	Timestamp string `json:"timestamp"`
+	APIKey    string `json:"api_key"`
+	Signature string `json:"signature"`
+}
+
+// TagResponse lists the public IDs a tag command was applied to.
+type TagResponse struct {
+	PublicIds []string `json:"public_ids"`
+}
+
+// AddTag attaches tag to every resource in publicIDs.
+func (rs *ResourceService) AddTag(ctx context.Context, resourceType, tag string, publicIDs []string) (*TagResponse, *Response, error) {
+	return rs.tagRequest(ctx, resourceType, "add", tag, publicIDs)
+}
+
+// RemoveTag detaches tag from every resource in publicIDs.
+func (rs *ResourceService) RemoveTag(ctx context.Context, resourceType, tag string, publicIDs []string) (*TagResponse, *Response, error) {
+	return rs.tagRequest(ctx, resourceType, "remove", tag, publicIDs)
+}
+
+// ReplaceTag removes every existing tag from publicIDs and replaces it
+// with tag.
+func (rs *ResourceService) ReplaceTag(ctx context.Context, resourceType, tag string, publicIDs []string) (*TagResponse, *Response, error) {
+	return rs.tagRequest(ctx, resourceType, "replace", tag, publicIDs)
+}
+
+func (rs *ResourceService) tagRequest(ctx context.Context, resourceType, command, tag string, publicIDs []string) (*TagResponse, *Response, error) {
+	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+	publicIdsParam := strings.Join(publicIDs, ",")
+
+	signParams := map[string]string{
+		"command":    command,
+		"tag":        tag,
+		"public_ids": publicIdsParam,
+		"timestamp":  timestamp,
+	}
+
+	body := &TagRequest{
+		Command:   command,
+		Tag:       tag,
+		PublicIds: publicIdsParam,
+		Timestamp: timestamp,
+		APIKey:    rs.client.apiKey,
+		Signature: sign(signParams, rs.client.apiSecret, SignatureSHA1),
+	}
+
+	req, err := rs.client.NewRequest("POST", fmt.Sprintf("%s/tags", resourceType), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tr := new(TagResponse)
+	resp, err := rs.client.Do(ctx, req, tr)
+	if err != nil {
+		return nil, resp, err
+	}
+	return tr, resp, nil
+}
+
+// ExplicitRequest is the signed body sent to POST /{resource_type}/explicit.
+type ExplicitRequest struct {
+	PublicId  string `json:"public_id"`
+	Type      string `json:"type,omitempty"`
+	Eager     string `json:"eager,omitempty"`
+	Timestamp string `json:"timestamp"`
+	APIKey    string `json:"api_key"`
+	Signature string `json:"signature"`
+}
+
+type explicitOptions struct {
+	resourceType string
+	deliveryType string
+	eager        string
+}
+
+// ExplicitOpt configures Explicit.
+type ExplicitOpt func(eo *explicitOptions)
+
+func WithExplicitResourceType(resourceType string) ExplicitOpt {
+	return func(eo *explicitOptions) {
+		eo.resourceType = resourceType
+	}
+}
+
+func WithExplicitType(deliveryType string) ExplicitOpt {
+	return func(eo *explicitOptions) {
+		eo.deliveryType = deliveryType
+	}
+}
+
+func WithExplicitEager(eager string) ExplicitOpt {
+	return func(eo *explicitOptions) {
+		eo.eager = eager
+	}
+}
+
+// Explicit triggers eager transformations on an already-uploaded
+// resource.
+func (rs *ResourceService) Explicit(ctx context.Context, publicID string, opts ...ExplicitOpt) (*UploadResponse, *Response, error) {
+	eo := &explicitOptions{resourceType: "image", deliveryType: "upload"}
+	for _, o := range opts {
+		o(eo)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+	signParams := map[string]string{
+		"public_id": publicID,
+		"type":      eo.deliveryType,
+		"timestamp": timestamp,
+	}
+	if eo.eager != "" {
+		signParams["eager"] = eo.eager
+	}
+
+	body := &ExplicitRequest{
+		PublicId:  publicID,
+		Type:      eo.deliveryType,
+		Eager:     eo.eager,
+		Timestamp: timestamp,
+		APIKey:    rs.client.apiKey,
+		Signature: sign(signParams, rs.client.apiSecret, SignatureSHA1),
+	}
+
+	req, err := rs.client.NewRequest("POST", fmt.Sprintf("%s/explicit", eo.resourceType), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ur := new(UploadResponse)
+	resp, err := rs.client.Do(ctx, req, ur)
+	if err != nil {
+		return nil, resp, err
+	}
+	return ur, resp, nil
+}
@@ -0,0 +1,62 @@
+package cloudinary
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+)
+
+// SignatureAlgorithm selects the hash function used to sign requests.
+// Cloudinary defaults to SHA-1; SHA-256 is opt-in via
+// WithSignatureAlgorithm.
+type SignatureAlgorithm int
+
+const (
+	SignatureSHA1 SignatureAlgorithm = iota
+	SignatureSHA256
+)
+
+func (a SignatureAlgorithm) newHash() hash.Hash {
+	if a == SignatureSHA256 {
+		return sha256.New()
+	}
+	return sha1.New()
+}
+
+// unsignedParamKeys lists the fields Cloudinary excludes from the
+// string-to-sign. file and signature are never sent to the signature
+// itself; cloud_name, resource_type and api_key are transport-level
+// concerns rather than signed parameters.
+var unsignedParamKeys = map[string]bool{
+	"file":          true,
+	"cloud_name":    true,
+	"resource_type": true,
+	"api_key":       true,
+	"signature":     true,
+}
+
+// sign computes the Cloudinary request signature: sortedParams joined as
+// "k1=v1&k2=v2" in lexicographic order, with apiSecret appended, hashed
+// with algo.
+func sign(params map[string]string, apiSecret string, algo SignatureAlgorithm) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if unsignedParamKeys[k] || params[k] == "" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+params[k])
+	}
+
+	h := algo.newHash()
+	h.Write([]byte(strings.Join(pairs, "&") + apiSecret))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,99 @@
+package cloudinary
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+func TestSignKnownVectors(t *testing.T) {
+	cases := []struct {
+		name      string
+		params    map[string]string
+		apiSecret string
+		algo      SignatureAlgorithm
+		toSign    string
+	}{
+		{
+			name:      "single param",
+			params:    map[string]string{"public_id": "sample", "timestamp": "1315060510"},
+			apiSecret: "abcd",
+			algo:      SignatureSHA1,
+			toSign:    "public_id=sample&timestamp=1315060510abcd",
+		},
+		{
+			name: "multiple params sorted lexicographically regardless of insertion order",
+			params: map[string]string{
+				"timestamp": "1315060510",
+				"folder":    "samples",
+				"public_id": "sample",
+			},
+			apiSecret: "abcd",
+			algo:      SignatureSHA1,
+			toSign:    "folder=samples&public_id=sample&timestamp=1315060510abcd",
+		},
+		{
+			name: "unsigned keys are excluded from the string-to-sign",
+			params: map[string]string{
+				"timestamp":     "1315060510",
+				"public_id":     "sample",
+				"file":          "should-be-excluded",
+				"cloud_name":    "should-be-excluded",
+				"resource_type": "should-be-excluded",
+				"api_key":       "should-be-excluded",
+				"signature":     "should-be-excluded",
+			},
+			apiSecret: "abcd",
+			algo:      SignatureSHA1,
+			toSign:    "public_id=sample&timestamp=1315060510abcd",
+		},
+		{
+			name:      "empty-valued params are excluded",
+			params:    map[string]string{"timestamp": "1315060510", "public_id": "sample", "folder": ""},
+			apiSecret: "abcd",
+			algo:      SignatureSHA1,
+			toSign:    "public_id=sample&timestamp=1315060510abcd",
+		},
+		{
+			name:      "sha256 opt-in",
+			params:    map[string]string{"public_id": "sample", "timestamp": "1315060510"},
+			apiSecret: "abcd",
+			algo:      SignatureSHA256,
+			toSign:    "public_id=sample&timestamp=1315060510abcd",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var want string
+			switch tc.algo {
+			case SignatureSHA256:
+				sum := sha256.Sum256([]byte(tc.toSign))
+				want = fmt.Sprintf("%x", sum)
+			default:
+				sum := sha1.Sum([]byte(tc.toSign))
+				want = fmt.Sprintf("%x", sum)
+			}
+
+			got := sign(tc.params, tc.apiSecret, tc.algo)
+			if got != want {
+				t.Fatalf("sign() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSignIsReproducible(t *testing.T) {
+	params := map[string]string{"public_id": "sample", "timestamp": "1315060510", "folder": "samples"}
+
+	first := sign(params, "abcd", SignatureSHA1)
+	second := sign(params, "abcd", SignatureSHA1)
+
+	if first != second {
+		t.Fatalf("sign() is not deterministic: %q != %q", first, second)
+	}
+	if first == "" {
+		t.Fatal("sign() returned an empty signature")
+	}
+}
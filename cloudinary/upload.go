@@ -1,14 +1,15 @@
 package cloudinary
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -21,8 +22,12 @@ type UploadService service
 type UploadRequest struct {
 	// Required fields to call upload request
 	File         string `json:"file"`
-	UploadPreset string `json:"upload_preset"`
+	UploadPreset string `json:"upload_preset,omitempty"`
 	Timestamp    string `json:"timestamp"`
+
+	// Set by applySignature unless the request is unsigned.
+	APIKey    string `json:"api_key,omitempty"`
+	Signature string `json:"signature,omitempty"`
 }
 
 type UploadOptions struct {
@@ -75,6 +80,12 @@ type UploadOptions struct {
 	Moderation        *string `json:"moderation,omitempty"`
 	Proxy             *string `json:"proxy,omitempty"`
 	ReturnDeleteToken *bool   `json:"return_delete_token,omitempty"`
+
+	// unsigned and sigAlgo are unexported so they never leak into the
+	// multipart/JSON params built from this struct; see WithUnsigned and
+	// WithSignatureAlgorithm.
+	unsigned bool
+	sigAlgo  SignatureAlgorithm
 }
 
 type UploadResponse struct {
@@ -153,6 +164,23 @@ func WithOverwrite(isOverwrite bool) Opt {
 	}
 }
 
+// WithUnsigned skips request signing, preserving the previous
+// preset-only upload behavior. Use it when the upload preset configured
+// in the Cloudinary console is itself marked unsigned.
+func WithUnsigned() Opt {
+	return func(uo *UploadOptions) {
+		uo.unsigned = true
+	}
+}
+
+// WithSignatureAlgorithm selects the hash used to sign the request.
+// Cloudinary defaults to SHA-1 when this option is not given.
+func WithSignatureAlgorithm(algo SignatureAlgorithm) Opt {
+	return func(uo *UploadOptions) {
+		uo.sigAlgo = algo
+	}
+}
+
 func (us *UploadService) UploadImage(ctx context.Context, request *UploadRequest, opts ...Opt) (*UploadResponse, *Response, error) {
 	u := fmt.Sprintf("image/upload")
 
@@ -161,6 +189,8 @@ func (us *UploadService) UploadImage(ctx context.Context, request *UploadRequest
 		o(opt)
 	}
 
+	us.applySignature(request, opt)
+
 	switch {
 	case strings.HasPrefix(request.File, "/"):
 		// Upload image using local path
@@ -178,14 +208,26 @@ func (us *UploadService) UploadImage(ctx context.Context, request *UploadRequest
 	}
 }
 
+// uploadFromURL also serves the s3:// and gs:// remote-fetch paths
+// (uploadFromS3, uploadFromGoogleStorage) when WithStreamRemote isn't
+// set, so opt must be folded into the request body here: paramsToSign
+// already signs opt's fields, and the server recomputes the same
+// signature over whatever params it receives, so any opt field dropped
+// from the body would make the signature invalid.
 func (us *UploadService) uploadFromURL(ctx context.Context, url string, request *UploadRequest, opt *UploadOptions) (*UploadResponse, *Response, error) {
-	req, err := us.client.NewRequest("POST", url, request)
-	if err != nil {
-		return nil, nil, err
+	params := us.paramsToSign(request, opt)
+	params["file"] = request.File
+	if request.APIKey != "" {
+		params["api_key"] = request.APIKey
+	}
+	if request.Signature != "" {
+		params["signature"] = request.Signature
 	}
 
 	ur := new(UploadResponse)
-	resp, err := us.client.Do(ctx, req, ur)
+	resp, err := us.client.doWithRetry(ctx, func() (*http.Request, error) {
+		return us.client.NewRequest("POST", url, params)
+	}, ur)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -194,33 +236,106 @@ func (us *UploadService) uploadFromURL(ctx context.Context, url string, request
 }
 
 func (us *UploadService) uploadFromLocalPath(ctx context.Context, url string, request *UploadRequest, opt *UploadOptions) (*UploadResponse, *Response, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	file, _, err := us.openFile(request.File)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
 
-	if request != nil {
-		if err := us.buildParamsFromRequest(request, writer); err != nil {
-			return nil, nil, err
-		}
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if stat.IsDir() {
+		return nil, nil, errors.New("the asset to upload can't be a directory")
 	}
 
-	if opt != nil {
-		if err := us.buildParamsFromOption(opt, writer); err != nil {
-			return nil, nil, err
-		}
+	return us.uploadStream(ctx, url, request, opt, file, stat.Size())
+}
+
+// UploadReader uploads content read from reader (a network stream, an
+// in-memory buffer, a bytes.Reader, ...) without requiring a local file
+// path. size is the number of bytes reader will yield, or -1 if
+// unknown.
+func (us *UploadService) UploadReader(ctx context.Context, reader io.Reader, size int64, request *UploadRequest, opts ...Opt) (*UploadResponse, *Response, error) {
+	opt := new(UploadOptions)
+	for _, o := range opts {
+		o(opt)
 	}
 
-	if err := writer.Close(); err != nil {
-		return nil, nil, err
+	us.applySignature(request, opt)
+
+	return us.uploadStream(ctx, "image/upload", request, opt, reader, size)
+}
+
+// uploadStream multipart-encodes request, opt and reader directly onto
+// an http.Request body via an io.Pipe, so a large asset is never
+// buffered in memory. size is reader's payload length (or -1 if
+// unknown); it is never set as the request's Content-Length, since the
+// multipart encoding adds boundaries and field headers on top of it, so
+// the transport streams the body with chunked encoding instead.
+//
+// When reader is a seekable *os.File, every attempt re-seeks to the
+// start and re-encodes the multipart body from scratch, which lets the
+// upload go through doWithRetry and be retried on 5xx/429. req.GetBody
+// is set the same way so the net/http client can itself replay the body
+// across redirects. Non-seekable sources (network streams, etc.) can
+// only be sent once, so they skip doWithRetry entirely.
+func (us *UploadService) uploadStream(ctx context.Context, url string, request *UploadRequest, opt *UploadOptions, reader io.Reader, size int64) (*UploadResponse, *Response, error) {
+	f, seekable := reader.(*os.File)
+	filename := "file"
+	if seekable {
+		filename = filepath.Base(f.Name())
 	}
 
-	req, err := us.client.NewUploadRequest(url, body, writer)
+	newReq := func() (*http.Request, error) {
+		body := reader
+		if seekable {
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			body = f
+		}
 
-	if err != nil {
-		return nil, nil, err
+		pr, writer := us.newMultipartPipe(request, opt, filename, body)
+
+		// The multipart encoding adds boundaries and field headers on top
+		// of the raw payload, so size (the payload's own length) is never
+		// the request's actual Content-Length; leave it unset and let the
+		// transport chunk the streamed body instead.
+		req, err := us.client.NewUploadRequest(url, pr, writer, -1)
+		if err != nil {
+			return nil, err
+		}
+
+		if seekable {
+			req.GetBody = func() (io.ReadCloser, error) {
+				if _, serr := f.Seek(0, io.SeekStart); serr != nil {
+					return nil, serr
+				}
+				replayPr, _ := us.newMultipartPipe(request, opt, filename, f)
+				return replayPr, nil
+			}
+		}
+
+		return req, nil
 	}
 
 	ur := new(UploadResponse)
-	resp, err := us.client.Do(ctx, req, ur)
+
+	if !seekable {
+		req, err := newReq()
+		if err != nil {
+			return nil, nil, err
+		}
+		resp, err := us.client.Do(ctx, req, ur)
+		if err != nil {
+			return nil, resp, err
+		}
+		return ur, resp, nil
+	}
+
+	resp, err := us.client.doWithRetry(ctx, newReq, ur)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -228,37 +343,106 @@ func (us *UploadService) uploadFromLocalPath(ctx context.Context, url string, re
 	return ur, resp, nil
 }
 
-func (us *UploadService) buildParamsFromRequest(request *UploadRequest, writer *multipart.Writer) error {
-	timeStamp := strconv.Itoa(int(time.Now().UTC().Unix())) + us.client.apiSecret
-	if err := writer.WriteField("timestamp", timeStamp); err != nil {
-		return err
-	}
+// newMultipartPipe builds a multipart.Writer that feeds an io.Pipe in a
+// background goroutine, so the caller can hand the pipe's read end
+// straight to http.Request.Body without materializing the whole body.
+func (us *UploadService) newMultipartPipe(request *UploadRequest, opt *UploadOptions, filename string, body io.Reader) (*io.PipeReader, *multipart.Writer) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		var err error
+		defer func() {
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			pw.Close()
+		}()
+
+		if request != nil {
+			if err = us.writeRequestFields(request, writer); err != nil {
+				return
+			}
+		}
+		if opt != nil {
+			if err = us.buildParamsFromOption(opt, writer); err != nil {
+				return
+			}
+		}
 
-	if err := writer.WriteField("upload_preset", request.UploadPreset); err != nil {
-		return err
+		var part io.Writer
+		part, err = writer.CreateFormFile("file", filename)
+		if err != nil {
+			return
+		}
+		if _, err = io.Copy(part, body); err != nil {
+			return
+		}
+		err = writer.Close()
+	}()
+
+	return pr, writer
+}
+
+// applySignature stamps request with a timestamp, api_key and signature
+// computed over request and opt, unless opt opted out via WithUnsigned.
+func (us *UploadService) applySignature(request *UploadRequest, opt *UploadOptions) {
+	if opt != nil && opt.unsigned {
+		return
 	}
 
-	file, _, err := us.openFile(request.File)
-	if err != nil {
-		return err
+	algo := SignatureSHA1
+	if opt != nil {
+		algo = opt.sigAlgo
 	}
-	defer file.Close()
 
-	stat, err := file.Stat()
-	if err != nil {
-		return err
+	request.Timestamp = strconv.FormatInt(time.Now().UTC().Unix(), 10)
+	request.APIKey = us.client.apiKey
+	request.Signature = sign(us.paramsToSign(request, opt), us.client.apiSecret, algo)
+}
+
+// paramsToSign collects the request and option fields that participate
+// in the Cloudinary signature. file, cloud_name, resource_type, api_key
+// and signature are excluded by sign itself.
+func (us *UploadService) paramsToSign(request *UploadRequest, opt *UploadOptions) map[string]string {
+	params := map[string]string{"timestamp": request.Timestamp}
+	if request.UploadPreset != "" {
+		params["upload_preset"] = request.UploadPreset
 	}
 
-	if stat.IsDir() {
-		return errors.New("the asset to upload can't be a directory")
+	if opt != nil {
+		var optMap map[string]interface{}
+		optByte, _ := json.Marshal(opt)
+		_ = json.Unmarshal(optByte, &optMap)
+		for field, val := range optMap {
+			params[field] = fmt.Sprintf("%v", val)
+		}
 	}
 
-	part, err := writer.CreateFormFile("file", file.Name())
-	if err != nil {
+	return params
+}
+
+func (us *UploadService) writeRequestFields(request *UploadRequest, writer *multipart.Writer) error {
+	if err := writer.WriteField("timestamp", request.Timestamp); err != nil {
 		return err
 	}
-	_, err = io.Copy(part, file)
-	return err
+	if request.UploadPreset != "" {
+		if err := writer.WriteField("upload_preset", request.UploadPreset); err != nil {
+			return err
+		}
+	}
+	if request.APIKey != "" {
+		if err := writer.WriteField("api_key", request.APIKey); err != nil {
+			return err
+		}
+	}
+	if request.Signature != "" {
+		if err := writer.WriteField("signature", request.Signature); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (us *UploadService) buildParamsFromOption(opt *UploadOptions, writer *multipart.Writer) error {
@@ -281,19 +465,14 @@ func (us *UploadService) buildParamsFromOption(opt *UploadOptions, writer *multi
 }
 
 func (us *UploadService) openFile(filePath string) (file *os.File, dir string, err error) {
-	dir, err = os.Getwd()
-	if err != nil {
-		return nil, dir, err
+	path := filePath
+	if !filepath.IsAbs(filePath) {
+		dir, err = os.Getwd()
+		if err != nil {
+			return nil, dir, err
+		}
+		path = filepath.Join(dir, filePath)
 	}
-	file, err = os.Open(dir + filePath)
+	file, err = os.Open(path)
 	return file, dir, err
-
-}
-
-func (us *UploadService) uploadFromS3(ctx context.Context, url string, request *UploadRequest, opt *UploadOptions) (*UploadResponse, *Response, error) {
-	return &UploadResponse{}, &Response{}, nil
-}
-
-func (us *UploadService) uploadFromGoogleStorage(ctx context.Context, url string, request *UploadRequest, opt *UploadOptions) (*UploadResponse, *Response, error) {
-	return &UploadResponse{}, &Response{}, nil
 }
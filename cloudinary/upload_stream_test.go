@@ -0,0 +1,123 @@
+package cloudinary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestUploadReaderNonSeekableSendsFileOnce(t *testing.T) {
+	want := []byte("non-seekable payload")
+
+	var requestCount int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm: %v", err)
+		}
+		for _, field := range []string{"timestamp", "api_key", "signature"} {
+			if r.FormValue(field) == "" {
+				t.Errorf("multipart form is missing required field %q", field)
+			}
+		}
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile: %v", err)
+		}
+		defer file.Close()
+		got, _ := io.ReadAll(file)
+		if !bytes.Equal(got, want) {
+			t.Errorf("file content = %q, want %q", got, want)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UploadResponse{PublicId: "stream-sample"})
+	}
+
+	c := newTestClient(t, handler)
+
+	// io.NopCloser hides the concrete type of the underlying reader, so
+	// it never type-asserts to *os.File and is treated as non-seekable.
+	reader := io.NopCloser(bytes.NewReader(want))
+
+	request := &UploadRequest{}
+	ur, resp, err := c.Upload.UploadReader(context.Background(), reader, int64(len(want)), request)
+	if err != nil {
+		t.Fatalf("UploadReader: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("UploadReader returned a nil Response")
+	}
+	if ur.PublicId != "stream-sample" {
+		t.Errorf("PublicId = %q, want %q", ur.PublicId, "stream-sample")
+	}
+	if requestCount != 1 {
+		t.Errorf("server saw %d requests, want 1 (non-seekable sources aren't retried)", requestCount)
+	}
+}
+
+func TestUploadFromLocalPathRetriesOnTransientFailureAndReplaysBody(t *testing.T) {
+	want := []byte("seekable payload replayed on retry")
+
+	f, err := os.CreateTemp(t.TempDir(), "upload-*.bin")
+	if err != nil {
+		t.Fatalf("CreateTemp: %v", err)
+	}
+	if _, err := f.Write(want); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var requestCount int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm: %v", err)
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile: %v", err)
+		}
+		defer file.Close()
+		got, _ := io.ReadAll(file)
+		if !bytes.Equal(got, want) {
+			t.Errorf("attempt %d file content = %q, want %q", requestCount, got, want)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if requestCount == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]string{"message": "simulated transient failure"},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(UploadResponse{PublicId: "local-path-sample"})
+	}
+
+	c := newTestClient(t, handler)
+
+	request := &UploadRequest{File: f.Name()}
+	ur, resp, err := c.Upload.UploadImage(context.Background(), request)
+	if err != nil {
+		t.Fatalf("UploadImage: %v", err)
+	}
+	if resp == nil {
+		t.Fatal("UploadImage returned a nil Response")
+	}
+	if ur.PublicId != "local-path-sample" {
+		t.Errorf("PublicId = %q, want %q", ur.PublicId, "local-path-sample")
+	}
+	if requestCount != 2 {
+		t.Errorf("server saw %d requests, want 2 (one failure then a retried success)", requestCount)
+	}
+}